@@ -0,0 +1,154 @@
+package st1201
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Codec is satisfied by any fixed-width float encoding this package offers,
+// letting callers swap between the linear ST 1201 mapping (*FPEncoder) and
+// the compact mantissa/exponent mapping (*CompactFloatEncoder) behind a
+// single interface.
+type Codec interface {
+	Encode(val float64) ([]byte, error)
+	Decode(data []byte) (float64, error)
+}
+
+// Base selects the radix a CompactFloatEncoder scales its mantissa by.
+type Base uint8
+
+const (
+	Base2  Base = 2
+	Base10 Base = 10
+)
+
+// CompactFloatEncoder packs a value as sign, exponent and mantissa fields
+// (a fixed-width float, in the style of Float16) rather than ST 1201's
+// linear min/max mapping. It suits fields whose dynamic range spans many
+// orders of magnitude, where a linear mapping would waste bits.
+type CompactFloatEncoder struct {
+	mantissaBits, exponentBits uint
+	base                       Base
+	fieldLength                int
+}
+
+// NewCompactFloatEncoder builds a base-10 CompactFloatEncoder with the given
+// mantissa and exponent widths, in bits. The encoded field length is the
+// smallest number of bytes holding a 1-bit sign plus those two fields.
+func NewCompactFloatEncoder(mantissaBits, exponentBits uint) (*CompactFloatEncoder, error) {
+	return NewCompactFloatEncoderWithBase(mantissaBits, exponentBits, Base10)
+}
+
+// NewCompactFloatEncoderWithBase is NewCompactFloatEncoder with an explicit
+// base; Base2 gives the usual binary floating-point scaling, Base10 suits
+// decimal/currency-like quantities.
+func NewCompactFloatEncoderWithBase(mantissaBits, exponentBits uint, base Base) (*CompactFloatEncoder, error) {
+	if base != Base2 && base != Base10 {
+		return nil, fmt.Errorf("base must be Base2 or Base10")
+	}
+
+	totalBits := 1 + exponentBits + mantissaBits
+	if totalBits == 0 || totalBits > 64 {
+		return nil, fmt.Errorf("sign bit + exponentBits + mantissaBits must fit in 64 bits, got %d", totalBits)
+	}
+
+	return &CompactFloatEncoder{
+		mantissaBits: mantissaBits,
+		exponentBits: exponentBits,
+		base:         base,
+		fieldLength:  int((totalBits + 7) / 8),
+	}, nil
+}
+
+// FieldLength returns the encoder's field length, in bytes.
+func (ce *CompactFloatEncoder) FieldLength() int {
+	return ce.fieldLength
+}
+
+// bias centers the stored (unsigned) exponent field on zero, the same way
+// IEEE floating-point formats do, so small-magnitude values (< 1) can still
+// pick a negative actual exponent instead of always underflowing to zero.
+func (ce *CompactFloatEncoder) bias() int64 {
+	maxExponentField := uint64(1)<<ce.exponentBits - 1
+	return int64(maxExponentField >> 1)
+}
+
+// Encode packs val as sign/exponent/mantissa. The exponent is the smallest
+// value for which the mantissa fits in mantissaBits, and the mantissa is
+// then floor()'d to that exponent: the encoded value does not exceed val in
+// magnitude. This floor behavior is asymmetric — Decode(Encode(val)) rounds
+// toward zero, not away from it. The floor is taken in float64 arithmetic,
+// and with Base10 a negative power of ten is not exact, so Decode's final
+// multiply can still land one ulp above val (1.7 can decode as
+// 1.7000000000000002).
+func (ce *CompactFloatEncoder) Encode(val float64) (encoded []byte, err error) {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return nil, fmt.Errorf("CompactFloatEncoder does not support Inf or NaN")
+	}
+
+	var sign uint64
+	magnitude := val
+	if math.Signbit(val) {
+		sign = 1
+		magnitude = -val
+	}
+
+	maxMantissa := uint64(1)<<ce.mantissaBits - 1
+	maxExponentField := uint64(1)<<ce.exponentBits - 1
+	bias := ce.bias()
+	baseF := float64(ce.base)
+
+	var storedField, mantissa uint64
+	if magnitude != 0 {
+		found := false
+		scale := math.Pow(baseF, float64(-bias))
+
+		for storedField = 0; storedField <= maxExponentField; storedField++ {
+			scaled := math.Floor(magnitude / scale)
+			if scaled <= float64(maxMantissa) {
+				mantissa = uint64(scaled)
+				found = true
+				break
+			}
+
+			scale *= baseF
+		}
+
+		if !found {
+			return nil, fmt.Errorf("value %v exceeds the largest representable exponent (field %d)", val, maxExponentField)
+		}
+	}
+
+	packed := (sign << (ce.exponentBits + ce.mantissaBits)) | (storedField << ce.mantissaBits) | mantissa
+
+	var full [8]byte
+	binary.BigEndian.PutUint64(full[:], packed)
+	return full[8-ce.fieldLength:], nil
+}
+
+// Decode unpacks a byte array produced by Encode back into a float64.
+func (ce *CompactFloatEncoder) Decode(data []byte) (val float64, err error) {
+	if len(data) != ce.fieldLength {
+		return 0, fmt.Errorf("Array length does not match expected field length")
+	}
+
+	var full [8]byte
+	copy(full[8-ce.fieldLength:], data)
+	packed := binary.BigEndian.Uint64(full[:])
+
+	mantissaMask := uint64(1)<<ce.mantissaBits - 1
+	exponentMask := uint64(1)<<ce.exponentBits - 1
+
+	mantissa := packed & mantissaMask
+	storedField := (packed >> ce.mantissaBits) & exponentMask
+	sign := (packed >> (ce.mantissaBits + ce.exponentBits)) & 1
+
+	exponent := int64(storedField) - ce.bias()
+	val = float64(mantissa) * math.Pow(float64(ce.base), float64(exponent))
+	if sign == 1 {
+		val = -val
+	}
+
+	return val, nil
+}
@@ -0,0 +1,71 @@
+package st1201
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCompactFloatEncoderRoundTrip(t *testing.T) {
+	ce, err := NewCompactFloatEncoder(4, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, ce.FieldLength(), 1)
+
+	encoded, err := ce.Encode(9999)
+	assert.NoError(t, err)
+
+	decoded, err := ce.Decode(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded, 9000.0)
+	assert.True(t, decoded <= 9999)
+}
+
+func TestCompactFloatEncoderExponentOverflow(t *testing.T) {
+	ce, _ := NewCompactFloatEncoder(4, 3)
+
+	_, err := ce.Encode(1e7)
+	assert.Error(t, err)
+}
+
+func TestCompactFloatEncoderNegativeAndZero(t *testing.T) {
+	ce, _ := NewCompactFloatEncoder(4, 3)
+
+	encoded, err := ce.Encode(-9000)
+	assert.NoError(t, err)
+	decoded, err := ce.Decode(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded, -9000.0)
+
+	encoded2, err := ce.Encode(0)
+	assert.NoError(t, err)
+	decoded2, err := ce.Decode(encoded2)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded2, 0.0)
+}
+
+func TestCompactFloatEncoderSubOneMagnitude(t *testing.T) {
+	ce, _ := NewCompactFloatEncoder(4, 3)
+
+	encoded, err := ce.Encode(0.05)
+	assert.NoError(t, err)
+
+	decoded, err := ce.Decode(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded, 0.05)
+}
+
+func TestCompactFloatEncoderBase2(t *testing.T) {
+	ce, err := NewCompactFloatEncoderWithBase(4, 3, Base2)
+	assert.NoError(t, err)
+
+	encoded, err := ce.Encode(100)
+	assert.NoError(t, err)
+
+	decoded, err := ce.Decode(encoded)
+	assert.NoError(t, err)
+	assert.True(t, decoded <= 100)
+}
+
+func TestCompactFloatEncoderSatisfiesCodec(t *testing.T) {
+	var _ Codec = (*FPEncoder)(nil)
+	var _ Codec = (*CompactFloatEncoder)(nil)
+}
@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
 )
 
@@ -27,6 +28,124 @@ type FPEncoder struct {
 
 var logOf2 float64 = math.Log(2.0)
 
+// specialKind identifies which ST 1201 "signal" a sentinel first byte represents.
+type specialKind uint8
+
+const (
+	noSpecial specialKind = iota
+	kindPositiveInfinity
+	kindNegativeInfinity
+	kindNaN
+	kindBelowMin
+	kindAboveMax
+	kindUserDefined
+)
+
+// Sentinel first-byte values defined by the ST. 0xc8, 0xe8 and 0xd0 are the
+// original three signals; 0xc9/0xe9 extend the "above maximum"/"below minimum"
+// families, and 0xf0-0xff reserve their low nibble for a user-defined identifier.
+const (
+	byteAboveMax    byte = 0xc9
+	byteBelowMin    byte = 0xe9
+	userDefinedBase byte = 0xf0
+	userDefinedMask byte = 0x0f
+)
+
+// SpecialValue identifies one of the non-numeric signals ST 1201 can carry
+// in place of a linearly-mapped value.
+type SpecialValue struct {
+	kind specialKind
+	id   uint8
+}
+
+// SpecialPositiveInfinity returns the signal for +Infinity (sentinel byte 0xc8).
+func SpecialPositiveInfinity() SpecialValue {
+	return SpecialValue{kind: kindPositiveInfinity}
+}
+
+// SpecialNegativeInfinity returns the signal for -Infinity (sentinel byte 0xe8).
+func SpecialNegativeInfinity() SpecialValue {
+	return SpecialValue{kind: kindNegativeInfinity}
+}
+
+// SpecialNaN returns the signal for NaN (sentinel byte 0xd0).
+func SpecialNaN() SpecialValue {
+	return SpecialValue{kind: kindNaN}
+}
+
+// SpecialBelowMin returns the signal indicating the true value fell below the
+// encoder's minimum (sentinel byte 0xe9).
+func SpecialBelowMin() SpecialValue {
+	return SpecialValue{kind: kindBelowMin}
+}
+
+// SpecialAboveMax returns the signal indicating the true value exceeded the
+// encoder's maximum (sentinel byte 0xc9).
+func SpecialAboveMax() SpecialValue {
+	return SpecialValue{kind: kindAboveMax}
+}
+
+// SpecialUserDefined returns a user-defined signal carrying id in the low
+// nibble of the sentinel byte. id must fit in 4 bits (0-15).
+func SpecialUserDefined(id uint8) (SpecialValue, error) {
+	if id > userDefinedMask {
+		return SpecialValue{}, fmt.Errorf("user-defined identifier must be in [0, %d], got %d", userDefinedMask, id)
+	}
+
+	return SpecialValue{kind: kindUserDefined, id: id}, nil
+}
+
+// IsUserDefined reports whether sv is a user-defined signal, returning its id if so.
+func (sv SpecialValue) IsUserDefined() (id uint8, ok bool) {
+	if sv.kind == kindUserDefined {
+		return sv.id, true
+	}
+
+	return 0, false
+}
+
+// sentinelByte returns the first-byte encoding for sv.
+func (sv SpecialValue) sentinelByte() (byte, error) {
+	switch sv.kind {
+	case kindPositiveInfinity:
+		return 0xc8, nil
+	case kindNegativeInfinity:
+		return 0xe8, nil
+	case kindNaN:
+		return 0xd0, nil
+	case kindAboveMax:
+		return byteAboveMax, nil
+	case kindBelowMin:
+		return byteBelowMin, nil
+	case kindUserDefined:
+		return userDefinedBase | (sv.id & userDefinedMask), nil
+	default:
+		return 0, fmt.Errorf("not a special value")
+	}
+}
+
+// specialFromByte recognizes a sentinel first byte, reporting the signal it
+// represents and whether b was a sentinel at all.
+func specialFromByte(b byte) (SpecialValue, bool) {
+	switch {
+	case b == 0xc8:
+		return SpecialPositiveInfinity(), true
+	case b == 0xe8:
+		return SpecialNegativeInfinity(), true
+	case b == 0xd0:
+		return SpecialNaN(), true
+	case b == byteAboveMax:
+		return SpecialAboveMax(), true
+	case b == byteBelowMin:
+		return SpecialBelowMin(), true
+	case b&0xf0 == userDefinedBase:
+		sv, _ := SpecialUserDefined(b & userDefinedMask)
+		return sv, true
+	default:
+		return SpecialValue{}, false
+	}
+}
+
 // Construct an encoder with the desired field length
 // min - The minimum floating point value to be encoded
 // max - The maximum floating point value to be encoded
@@ -49,22 +168,32 @@ func NewFPEncoderWithLength(min, max float64, length int) (fpe *FPEncoder, err e
 func NewFPEncoderWithPrecision(min, max, precision float64) (fpe *FPEncoder, err error) {
 	fpe = new(FPEncoder)
 
+	length, err := RequiredLength(min, max, precision)
+	if err != nil {
+		return nil, err
+	}
+
+	fpe.preCompute(min, max, length)
+	return
+}
+
+// RequiredLength returns the field length, in bytes, needed to encode values
+// in [min, max] to at least the requested precision, without constructing an
+// encoder. It is useful when sizing a KLV schema ahead of time.
+func RequiredLength(min, max, precision float64) (int, error) {
 	bits := math.Ceil(log2((max-min)/precision) + 1)
 
 	length := math.Ceil(bits / 8)
 
 	if length <= 2 {
-		fpe.preCompute(min, max, int(length))
-		return
+		return int(length), nil
 	} else if length <= 4 {
-		fpe.preCompute(min, max, 4)
-		return
+		return 4, nil
 	} else if length <= 8 {
-		fpe.preCompute(min, max, 8)
-		return
+		return 8, nil
 	}
 
-	return nil, fmt.Errorf("The specified range and precision cannot be represented using a 64-bit integer")
+	return 0, fmt.Errorf("The specified range and precision cannot be represented using a 64-bit integer")
 }
 
 // Encode a floating point value as a byte array
@@ -144,6 +273,141 @@ func (fpe *FPEncoder) Decode(bytes []byte) (val float64, err error) {
 	return
 }
 
+// encodeInto fills buf, which must already be fpe.fieldLength bytes long,
+// with the encoding of val. It performs no allocation, writing directly via
+// binary.BigEndian so it can be reused by both EncodeAppend and EncodeTo.
+func (fpe *FPEncoder) encodeInto(buf []byte, val float64) error {
+	for i := range buf {
+		buf[i] = 0
+	}
+
+	if val == math.Inf(0) {
+		buf[0] = 0xc8
+	} else if val == math.Inf(-1) {
+		buf[0] = 0xe8
+	} else if math.IsNaN(val) {
+		buf[0] = 0xd0
+	} else if val < fpe.a || val > fpe.b {
+		return fmt.Errorf("Value must be in range [ %v, %v]", fpe.a, fpe.b)
+	} else {
+		d := math.Floor(fpe.sF*(val-fpe.a) + fpe.zOffset)
+		switch fpe.fieldLength {
+		case 1:
+			buf[0] = byte(d)
+		case 2:
+			binary.BigEndian.PutUint16(buf, uint16(d))
+		case 4:
+			binary.BigEndian.PutUint32(buf, uint32(d))
+		case 8:
+			binary.BigEndian.PutUint64(buf, uint64(d))
+		}
+	}
+
+	return nil
+}
+
+// EncodeAppend encodes val and appends it to dst, returning the extended
+// slice. Unlike Encode, it grows dst in place instead of allocating a fresh
+// bytes.Buffer per call, which matters when encoding long runs of samples.
+func (fpe *FPEncoder) EncodeAppend(dst []byte, val float64) ([]byte, error) {
+	start := len(dst)
+	dst = append(dst, make([]byte, fpe.fieldLength)...)
+
+	if err := fpe.encodeInto(dst[start:], val); err != nil {
+		return dst[:start], err
+	}
+
+	return dst, nil
+}
+
+// EncodeTo encodes each value in vals and writes it to w, reusing a single
+// scratch buffer sized to fpe.fieldLength rather than allocating per sample.
+// n is the number of bytes successfully written before any error.
+func (fpe *FPEncoder) EncodeTo(w io.Writer, vals []float64) (n int, err error) {
+	scratch := make([]byte, fpe.fieldLength)
+
+	for _, val := range vals {
+		if err = fpe.encodeInto(scratch, val); err != nil {
+			return n, err
+		}
+
+		var wn int
+		if wn, err = w.Write(scratch); err != nil {
+			return n + wn, err
+		}
+
+		n += wn
+	}
+
+	return n, nil
+}
+
+// DecodeFrom reads len(out) encoded values from r into out, reusing a single
+// scratch buffer sized to fpe.fieldLength. n is the number of values
+// successfully decoded before any error, including io.EOF once r is exhausted.
+func (fpe *FPEncoder) DecodeFrom(r io.Reader, out []float64) (n int, err error) {
+	scratch := make([]byte, fpe.fieldLength)
+
+	for n = 0; n < len(out); n++ {
+		if _, err = io.ReadFull(r, scratch); err != nil {
+			return n, err
+		}
+
+		if out[n], err = fpe.Decode(scratch); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// Precision returns sR, the reverse scale factor. sR is the weight of one
+// LSB of the encoded integer and so bounds the worst-case round-off
+// introduced by encoding: any value decodes to within sR of its original.
+func (fpe *FPEncoder) Precision() float64 {
+	return fpe.sR
+}
+
+// FieldLength returns the encoder's field length, in bytes.
+func (fpe *FPEncoder) FieldLength() int {
+	return fpe.fieldLength
+}
+
+// Range returns the encoder's configured minimum and maximum values.
+func (fpe *FPEncoder) Range() (min, max float64) {
+	return fpe.a, fpe.b
+}
+
+// EncodeSpecial encodes one of ST 1201's non-numeric signals, such as
+// SpecialAboveMax() or a SpecialUserDefined(id) identifier, as a byte array
+// of the encoder's field length.
+func (fpe *FPEncoder) EncodeSpecial(sv SpecialValue) (encoded []byte, err error) {
+	b, err := sv.sentinelByte()
+	if err != nil {
+		return nil, err
+	}
+
+	encoded = make([]byte, fpe.fieldLength)
+	encoded[0] = b
+	return
+}
+
+// DecodeAny decodes a byte array that may contain either a linearly-mapped
+// value or one of ST 1201's special signals. ok reports whether data carried
+// a signal; when it does, val is left at its zero value.
+func (fpe *FPEncoder) DecodeAny(data []byte) (val float64, sv SpecialValue, ok bool, err error) {
+	if len(data) != fpe.fieldLength {
+		return 0, SpecialValue{}, false, fmt.Errorf("Array length does not match expected field length")
+	}
+
+	if sv, ok = specialFromByte(data[0]); ok {
+		return 0, sv, true, nil
+	}
+
+	val, err = fpe.Decode(data)
+	return val, SpecialValue{}, false, err
+}
+
 // Compute constants used for encoding and decoding
 // min - The minimum floating point value to be encoded
 // max - The maximum floating point value to be encoded
@@ -1,9 +1,11 @@
 package st1201
 
 import (
+	"bytes"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"io"
 	"math"
+	"testing"
 )
 
 func TestLength(t *testing.T) {
@@ -62,3 +64,139 @@ func TestDecode(t *testing.T) {
 	assert.True(t, math.IsNaN(val4))
 
 }
+
+func TestEncodeSpecial(t *testing.T) {
+	fp, _ := NewFPEncoderWithLength(0.0, 1e9, 8)
+
+	encoded, err := fp.EncodeSpecial(SpecialAboveMax())
+	assert.NoError(t, err)
+	assert.Equal(t, encoded[0], byte(0xc9))
+
+	encoded2, err := fp.EncodeSpecial(SpecialBelowMin())
+	assert.NoError(t, err)
+	assert.Equal(t, encoded2[0], byte(0xe9))
+
+	id, err := SpecialUserDefined(5)
+	assert.NoError(t, err)
+	encoded3, err := fp.EncodeSpecial(id)
+	assert.NoError(t, err)
+	assert.Equal(t, encoded3[0], byte(0xf5))
+
+	_, err = SpecialUserDefined(16)
+	assert.Error(t, err)
+
+	_, err = fp.EncodeSpecial(SpecialValue{})
+	assert.Error(t, err)
+}
+
+func TestDecodeAny(t *testing.T) {
+	fp, _ := NewFPEncoderWithLength(0.0, 1e9, 8)
+
+	encoded := []byte{0x00, 0x00, 0x00, 0x06, 0x48, 0x7e, 0x7c, 0x06}
+	val, sv, ok, err := fp.DecodeAny(encoded)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, sv, SpecialValue{})
+	assert.InEpsilon(t, val, 3.14159, 1e-8)
+
+	aboveMax := []byte{0xc9, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	_, sv2, ok2, err := fp.DecodeAny(aboveMax)
+	assert.NoError(t, err)
+	assert.True(t, ok2)
+	assert.Equal(t, sv2, SpecialAboveMax())
+
+	userID := []byte{0xf7, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	_, sv3, ok3, err := fp.DecodeAny(userID)
+	assert.NoError(t, err)
+	assert.True(t, ok3)
+	id, isUser := sv3.IsUserDefined()
+	assert.True(t, isUser)
+	assert.Equal(t, id, uint8(7))
+}
+
+func TestEncodeAppend(t *testing.T) {
+	fp, _ := NewFPEncoderWithLength(0.0, 1e9, 8)
+
+	dst := []byte{0xff}
+	dst, err := fp.EncodeAppend(dst, 3.14159)
+	assert.NoError(t, err)
+	assert.Equal(t, dst, []byte{0xff, 0x00, 0x00, 0x00, 0x06, 0x48, 0x7e, 0x7c, 0x06})
+
+	_, err = fp.EncodeAppend(nil, -1.0)
+	assert.Error(t, err)
+}
+
+func TestEncodeToDecodeFrom(t *testing.T) {
+	fp, _ := NewFPEncoderWithLength(0.0, 1e9, 8)
+
+	vals := []float64{3.14159, 0.0, 1e9, math.Inf(1)}
+
+	var buf bytes.Buffer
+	n, err := fp.EncodeTo(&buf, vals)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 8*len(vals))
+
+	out := make([]float64, len(vals))
+	n2, err := fp.DecodeFrom(&buf, out)
+	assert.NoError(t, err)
+	assert.Equal(t, n2, len(vals))
+	assert.InEpsilon(t, out[0], 3.14159, 1e-8)
+	assert.Equal(t, out[3], math.Inf(1))
+
+	n3, err := fp.DecodeFrom(&buf, make([]float64, 1))
+	assert.Equal(t, err, io.EOF)
+	assert.Equal(t, n3, 0)
+}
+
+func TestPrecisionFieldLengthRange(t *testing.T) {
+	fp, _ := NewFPEncoderWithPrecision(0.0, 100.0, 0.1)
+
+	assert.Equal(t, fp.FieldLength(), 2)
+
+	min, max := fp.Range()
+	assert.Equal(t, min, 0.0)
+	assert.Equal(t, max, 100.0)
+
+	assert.True(t, fp.Precision() <= 0.1)
+}
+
+func TestRequiredLength(t *testing.T) {
+	length, err := RequiredLength(0.0, 100.0, 0.1)
+	assert.NoError(t, err)
+	assert.Equal(t, length, 2)
+
+	fp, _ := NewFPEncoderWithPrecision(0.0, 100.0, 0.1)
+	assert.Equal(t, length, fp.FieldLength())
+
+	_, err = RequiredLength(0.0, 1e300, 1e-300)
+	assert.Error(t, err)
+}
+
+func BenchmarkEncode(b *testing.B) {
+	fp, _ := NewFPEncoderWithLength(0.0, 1e9, 8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fp.Encode(3.14159)
+	}
+}
+
+func BenchmarkEncodeAppend(b *testing.B) {
+	fp, _ := NewFPEncoderWithLength(0.0, 1e9, 8)
+	dst := make([]byte, 0, 8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst, _ = fp.EncodeAppend(dst[:0], 3.14159)
+	}
+}
+
+func BenchmarkEncodeTo(b *testing.B) {
+	fp, _ := NewFPEncoderWithLength(0.0, 1e9, 8)
+	vals := make([]float64, 1000)
+	for i := range vals {
+		vals[i] = float64(i)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fp.EncodeTo(io.Discard, vals)
+	}
+}
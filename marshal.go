@@ -0,0 +1,230 @@
+package st1201
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// planCache holds a compiled *typePlan per struct type, so repeated
+// Marshal/Unmarshal calls for the same type (e.g. a slice of records) only
+// walk the type's fields once via reflection.
+var planCache sync.Map
+
+// fieldPlan describes how to read/write a single struct field.
+// enc is non-nil for fields carrying an `st1201` tag; nil fields fall
+// through to plain encoding/binary big-endian semantics.
+type fieldPlan struct {
+	idx int
+	enc *FPEncoder
+}
+
+type typePlan struct {
+	typ    reflect.Type
+	fields []fieldPlan
+}
+
+// RegisterType pre-compiles and caches the field plan for t, so the first
+// Marshal or Unmarshal call involving that type doesn't pay the reflection
+// walk. t must be a struct type, not a pointer.
+func RegisterType(t reflect.Type) error {
+	_, err := planFor(t)
+	return err
+}
+
+// Marshal encodes v, which must be a struct or a pointer to one, into a
+// byte slice. Fields tagged `st1201:"min=...,max=...,len=...[,precision=...]"`
+// are encoded with an FPEncoder built from the tag; all other exported
+// fields fall through to encoding/binary big-endian semantics.
+func Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("st1201: Marshal requires a struct or pointer to struct, got %s", rv.Kind())
+	}
+
+	plan, err := planFor(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, fp := range plan.fields {
+		fv := rv.Field(fp.idx)
+
+		if fp.enc != nil {
+			encoded, err := fp.enc.Encode(fv.Float())
+			if err != nil {
+				return nil, fmt.Errorf("st1201: field %s: %v", plan.typ.Field(fp.idx).Name, err)
+			}
+
+			buf.Write(encoded)
+			continue
+		}
+
+		if err := binary.Write(&buf, binary.BigEndian, fv.Interface()); err != nil {
+			return nil, fmt.Errorf("st1201: field %s: %v", plan.typ.Field(fp.idx).Name, err)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes data into v, which must be a pointer to a struct,
+// using the same field plan Marshal would use for that type.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("st1201: Unmarshal requires a pointer to struct, got %s", rv.Kind())
+	}
+
+	rv = rv.Elem()
+
+	plan, err := planFor(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	r := bytes.NewReader(data)
+	for _, fp := range plan.fields {
+		fv := rv.Field(fp.idx)
+
+		if fp.enc != nil {
+			scratch := make([]byte, fp.enc.fieldLength)
+			if _, err := io.ReadFull(r, scratch); err != nil {
+				return fmt.Errorf("st1201: field %s: %v", plan.typ.Field(fp.idx).Name, err)
+			}
+
+			val, err := fp.enc.Decode(scratch)
+			if err != nil {
+				return fmt.Errorf("st1201: field %s: %v", plan.typ.Field(fp.idx).Name, err)
+			}
+
+			fv.SetFloat(val)
+			continue
+		}
+
+		if err := binary.Read(r, binary.BigEndian, fv.Addr().Interface()); err != nil {
+			return fmt.Errorf("st1201: field %s: %v", plan.typ.Field(fp.idx).Name, err)
+		}
+	}
+
+	return nil
+}
+
+// planFor returns the cached *typePlan for t, compiling and storing one if
+// this is the first time t has been seen.
+func planFor(t reflect.Type) (*typePlan, error) {
+	if v, ok := planCache.Load(t); ok {
+		return v.(*typePlan), nil
+	}
+
+	plan, err := compilePlan(t)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, _ := planCache.LoadOrStore(t, plan)
+	return actual.(*typePlan), nil
+}
+
+// compilePlan walks t's fields via reflection once, resolving each
+// `st1201`-tagged float field to a ready-built FPEncoder.
+func compilePlan(t reflect.Type) (*typePlan, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("st1201: %s is not a struct", t)
+	}
+
+	plan := &typePlan{typ: t}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag, ok := sf.Tag.Lookup("st1201")
+		if !ok {
+			plan.fields = append(plan.fields, fieldPlan{idx: i})
+			continue
+		}
+
+		if sf.Type.Kind() != reflect.Float64 && sf.Type.Kind() != reflect.Float32 {
+			return nil, fmt.Errorf("st1201: field %s: st1201 tag only applies to float fields", sf.Name)
+		}
+
+		enc, err := encoderFromTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("st1201: field %s: %v", sf.Name, err)
+		}
+
+		plan.fields = append(plan.fields, fieldPlan{idx: i, enc: enc})
+	}
+
+	return plan, nil
+}
+
+// encoderFromTag builds an FPEncoder from an `st1201` struct tag of the form
+// "min=...,max=...,len=..." or "min=...,max=...,precision=...". If both len
+// and precision are present, len takes precedence.
+func encoderFromTag(tag string) (*FPEncoder, error) {
+	var min, max, precision float64
+	var length int
+	var haveMin, haveMax, haveLen, havePrecision bool
+	var err error
+
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("malformed tag element %q", part)
+		}
+
+		key, val := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch key {
+		case "min":
+			if min, err = strconv.ParseFloat(val, 64); err != nil {
+				return nil, fmt.Errorf("invalid min %q: %v", val, err)
+			}
+			haveMin = true
+		case "max":
+			if max, err = strconv.ParseFloat(val, 64); err != nil {
+				return nil, fmt.Errorf("invalid max %q: %v", val, err)
+			}
+			haveMax = true
+		case "len":
+			if length, err = strconv.Atoi(val); err != nil {
+				return nil, fmt.Errorf("invalid len %q: %v", val, err)
+			}
+			haveLen = true
+		case "precision":
+			if precision, err = strconv.ParseFloat(val, 64); err != nil {
+				return nil, fmt.Errorf("invalid precision %q: %v", val, err)
+			}
+			havePrecision = true
+		default:
+			return nil, fmt.Errorf("unknown tag key %q", key)
+		}
+	}
+
+	if !haveMin || !haveMax {
+		return nil, fmt.Errorf("tag must specify min and max")
+	}
+
+	if haveLen {
+		return NewFPEncoderWithLength(min, max, length)
+	}
+
+	if havePrecision {
+		return NewFPEncoderWithPrecision(min, max, precision)
+	}
+
+	return nil, fmt.Errorf("tag must specify len or precision")
+}
@@ -0,0 +1,71 @@
+package st1201
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+type sampleRecord struct {
+	Latitude  float64 `st1201:"min=-90,max=90,len=4"`
+	Longitude float64 `st1201:"min=-180,max=180,len=4"`
+	Altitude  float64 `st1201:"min=-900,max=19000,precision=1"`
+	Count     int32
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	in := sampleRecord{Latitude: 12.34, Longitude: -56.78, Altitude: 1000.0, Count: 42}
+
+	data, err := Marshal(&in)
+	assert.NoError(t, err)
+
+	var out sampleRecord
+	err = Unmarshal(data, &out)
+	assert.NoError(t, err)
+
+	assert.InDelta(t, in.Latitude, out.Latitude, 1e-3)
+	assert.InDelta(t, in.Longitude, out.Longitude, 1e-3)
+	assert.InDelta(t, in.Altitude, out.Altitude, 1.0)
+	assert.Equal(t, in.Count, out.Count)
+}
+
+func TestMarshalRejectsNonFloatTag(t *testing.T) {
+	type bad struct {
+		X int32 `st1201:"min=0,max=10,len=1"`
+	}
+
+	_, err := Marshal(&bad{})
+	assert.Error(t, err)
+}
+
+func TestMarshalSliceOfStructReusesCachedPlan(t *testing.T) {
+	records := make([]sampleRecord, 100)
+	for i := range records {
+		records[i] = sampleRecord{Latitude: float64(i % 90), Longitude: -10, Altitude: 500, Count: int32(i)}
+	}
+
+	for _, r := range records {
+		data, err := Marshal(&r)
+		assert.NoError(t, err)
+
+		var out sampleRecord
+		assert.NoError(t, Unmarshal(data, &out))
+		assert.Equal(t, r.Count, out.Count)
+	}
+
+	if _, ok := planCache.Load(reflect.TypeOf(sampleRecord{})); !ok {
+		t.Fatal("expected plan to be cached after marshaling")
+	}
+}
+
+func TestRegisterTypePreWarmsCache(t *testing.T) {
+	type preWarmed struct {
+		V float64 `st1201:"min=0,max=1,len=1"`
+	}
+
+	typ := reflect.TypeOf(preWarmed{})
+	assert.NoError(t, RegisterType(typ))
+
+	_, ok := planCache.Load(typ)
+	assert.True(t, ok)
+}